@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
@@ -19,8 +20,21 @@ type PF struct {
 	PfctlCommand string
 	PfctlArgs    []string
 	UseSudo      bool
+	Method       string
+	Stanzas      []string
+	Interfaces   []string
 	StateTable   []*Entry
-	infoFunc     func() (string, error)
+
+	infoFunc        func() (string, error)
+	ioctlFunc       func() (map[string]interface{}, error)
+	labelsFunc      func() (string, error)
+	tablesFunc      func() (string, error)
+	queuesFunc      func() (string, error)
+	anchorsFunc     func() (string, error)
+	anchorRulesFunc func(anchor string) (string, error)
+	interfacesFunc  func() (string, error)
+
+	ifaceFilter filter.Filter
 }
 
 func (pf *PF) Description() string {
@@ -36,30 +50,104 @@ func (pf *PF) SampleConfig() string {
   ## Example /etc/doas.conf (replace USERNAME as appropriate)
   ## permit nopass USERNAME as root cmd /sbin/pfctl args -s info
   use_sudo = false
+
+  ## 'method' selects how PF stats are collected.
+  ##   "pfctl" (default) execs pfctl and parses its output.
+  ##   "ioctl" talks to /dev/pf directly, requiring the telegraf user to be
+  ##   a member of the '_pf' group with read access to /dev/pf instead of
+  ##   root/doas. Only available on FreeBSD and OpenBSD.
+  method = "pfctl"
+
+  ## 'stanzas' selects which pfctl-backed measurements to collect.
+  ##   "info"    (default) the global Interface Stats/State Table/Counters,
+  ##             emitted as the "pf" measurement. This is the only stanza
+  ##             the "ioctl" method currently supports.
+  ##   "labels"  per-rule label counters ("pfctl -sl"), emitted as
+  ##             "pf_labels" tagged by "label".
+  ##   "tables"  per-table statistics ("pfctl -sT -vv"), emitted as
+  ##             "pf_tables" tagged by "table".
+  ##   "queues"  ALTQ queue statistics ("pfctl -sq -v"), emitted as
+  ##             "pf_queues" tagged by "queue" and "interface".
+  ##   "anchors" per-anchor rule counts ("pfctl -sA"), emitted as
+  ##             "pf_anchors" tagged by "anchor".
+  stanzas = ["info"]
+
+  ## 'interfaces' additionally runs "pfctl -vvsI" (list all interfaces
+  ## statistics) and emits one "pf" point per matching interface, tagged by
+  ## "interface", instead of being limited to the single loginterface set in
+  ## pf.conf. Interface names are glob-matched against this list. Left unset
+  ## by default to keep the single-loginterface behavior from the "info"
+  ## stanza.
+  # interfaces = ["*"]
 `
 }
 
 // Gather is the entrypoint for the plugin.
 func (pf *PF) Gather(acc telegraf.Accumulator) error {
+	if pf.Method == "" {
+		pf.Method = "pfctl"
+	}
+	if len(pf.Stanzas) == 0 {
+		pf.Stanzas = []string{"info"}
+	}
+
+	if pf.Method == "ioctl" {
+		fields, err := pf.ioctlFunc()
+		if err != nil {
+			acc.AddError(err)
+			return nil
+		}
+		acc.AddFields(measurement, fields, make(map[string]string))
+		return nil
+	}
+
 	pf.UseSudo = true
+	for _, stanza := range pf.Stanzas {
+		var err error
+		switch stanza {
+		case "info":
+			err = pf.gatherInfo(acc)
+		case "labels":
+			err = pf.gatherLabels(acc)
+		case "tables":
+			err = pf.gatherTables(acc)
+		case "queues":
+			err = pf.gatherQueues(acc)
+		case "anchors":
+			err = pf.gatherAnchors(acc)
+		default:
+			err = fmt.Errorf("unknown pf stanza %q", stanza)
+		}
+		if err != nil {
+			acc.AddError(err)
+		}
+	}
+
+	if len(pf.Interfaces) > 0 {
+		if err := pf.gatherInterfaces(acc); err != nil {
+			acc.AddError(err)
+		}
+	}
+
+	return nil
+}
+
+// gatherInfo runs "pfctl -s info" and parses the global Interface
+// Stats/State Table/Counters sections into the "pf" measurement.
+func (pf *PF) gatherInfo(acc telegraf.Accumulator) error {
 	if pf.PfctlCommand == "" {
 		var err error
 		if pf.PfctlCommand, pf.PfctlArgs, err = pf.buildPfctlCmd(); err != nil {
-			acc.AddError(fmt.Errorf("Can't construct pfctl commandline: %s", err))
-			return nil
+			return fmt.Errorf("Can't construct pfctl commandline: %s", err)
 		}
 	}
 
 	o, err := pf.infoFunc()
 	if err != nil {
-		acc.AddError(err)
-		return nil
+		return err
 	}
 
-	if perr := pf.parsePfctlOutput(o, acc); perr != nil {
-		acc.AddError(perr)
-	}
-	return nil
+	return pf.parsePfctlOutput(o, acc)
 }
 
 var errParseHeader = fmt.Errorf("Cannot find header in %s output", pfctlCommand)
@@ -102,6 +190,64 @@ var bytesRE = regexp.MustCompile(`^(\s+Bytes In|\s+Bytes Out)\s+(\d+)\s+(\d+)`)
 // regexp to extract from Packets Passed/Blocked
 var IPvRE = regexp.MustCompile(`^\s+(.*?)\s+(\d+)\s+(\d+)`)
 
+// consumeStanzaLines reads the body of a stanza from scanner, normalizing
+// the "Packets In"/"Packets Out" and "Bytes In"/"Bytes Out" blocks pfctl
+// prints into synthetic "<title> IPv4 <value>"/"<title> IPv6 <value>" lines
+// that storeFieldValues can match against. startLine is the first line of
+// the stanza body (already read from scanner by the caller). It stops at
+// the next stanza header (matched by anyTableHeaderRE) or EOF, returning
+// that header line ("" at EOF) so the caller can keep scanning from there.
+func consumeStanzaLines(scanner *bufio.Scanner, startLine string) ([]string, string) {
+	var stanzaLines []string
+	line := startLine
+	for !anyTableHeaderRE.MatchString(line) {
+		// try to match the Packets groups
+		if entries := packetsRE.FindStringSubmatch(line); entries != nil {
+			// assume there are two lines next we are interested in
+			// the Passed and Blocked
+			for i := 0; i < 2; i++ {
+				more := scanner.Scan()
+				if more {
+					line = scanner.Text()
+					// instead of using the original info because it's the same for in/out
+					// we inject with distinguishing information so the field
+					// extractor can work nicely
+					// prepend with the original string because regexp expects spaces
+					statsEntries := IPvRE.FindStringSubmatch(line)
+					if statsEntries != nil {
+						// entries[1] is "  Packets In" or "  Packets Out"
+						// statsEntries[1] is "Passed" or "Blocked"
+						// statsEntries[2] is IPv4 value
+						// statsEntries[3] is IPv6 value
+						newline := fmt.Sprintf("%s %s IPv4 %s", entries[1], statsEntries[1], statsEntries[2])
+						stanzaLines = append(stanzaLines, newline)
+						newline = fmt.Sprintf("%s %s IPv6 %s", entries[1], statsEntries[1], statsEntries[3])
+						stanzaLines = append(stanzaLines, newline)
+					}
+				}
+			}
+		} else if entries := bytesRE.FindStringSubmatch(line); entries != nil {
+			// try to match the Bytes In and Bytes out from Interface Stats
+			// entries[1] is "  Bytes In" or "  Bytes Out"
+			// entries[2] is IPv4 value
+			// entries[3] is IPv6 value
+			newline := fmt.Sprintf("%s IPv4 %s", entries[1], entries[2])
+			stanzaLines = append(stanzaLines, newline)
+			newline = fmt.Sprintf("%s IPv6 %s", entries[1], entries[3])
+			stanzaLines = append(stanzaLines, newline)
+		} else {
+			stanzaLines = append(stanzaLines, line)
+		}
+		more := scanner.Scan()
+		if more {
+			line = scanner.Text()
+		} else {
+			return stanzaLines, ""
+		}
+	}
+	return stanzaLines, line
+}
+
 func (pf *PF) parsePfctlOutput(pfoutput string, acc telegraf.Accumulator) error {
 	fields := make(map[string]interface{})
 	scanner := bufio.NewScanner(strings.NewReader(pfoutput))
@@ -109,54 +255,9 @@ func (pf *PF) parsePfctlOutput(pfoutput string, acc telegraf.Accumulator) error
 		line := scanner.Text()
 		for _, s := range pfctlOutputStanzas {
 			if s.HeaderRE.MatchString(line) {
-				var stanzaLines []string
 				scanner.Scan()
-				line = scanner.Text()
-				for !anyTableHeaderRE.MatchString(line) {
-					// try to match the Packets groups
-					if entries := packetsRE.FindStringSubmatch(line); entries != nil {
-						// assume there are two lines next we are interested in
-						// the Passed and Blocked
-						for i := 0; i < 2; i++ {
-							more := scanner.Scan()
-							if more {
-								line = scanner.Text()
-								// instead of using the original info because it's the same for in/out
-								// we inject with distinguishing information so the field
-								// extractor can work nicely
-								// prepend with the original string because regexp expects spaces
-								statsEntries := IPvRE.FindStringSubmatch(line)
-								if statsEntries != nil {
-									// entries[1] is "  Packets In" or "  Packets Out"
-									// statsEntries[1] is "Passed" or "Blocked"
-									// statsEntries[2] is IPv4 value
-									// statsEntries[3] is IPv6 value
-									newline := fmt.Sprintf("%s %s IPv4 %s", entries[1], statsEntries[1], statsEntries[2])
-									stanzaLines = append(stanzaLines, newline)
-									newline = fmt.Sprintf("%s %s IPv6 %s", entries[1], statsEntries[1], statsEntries[3])
-									stanzaLines = append(stanzaLines, newline)
-								}
-							}
-						}
-					} else if entries := bytesRE.FindStringSubmatch(line); entries != nil {
-						// try to match the Bytes In and Bytes out from Interface Stats
-						// entries[1] is "  Bytes In" or "  Bytes Out"
-						// entries[2] is IPv4 value
-						// entries[3] is IPv6 value
-						newline := fmt.Sprintf("%s IPv4 %s", entries[1], entries[2])
-						stanzaLines = append(stanzaLines, newline)
-						newline = fmt.Sprintf("%s IPv6 %s", entries[1], entries[3])
-						stanzaLines = append(stanzaLines, newline)
-					} else {
-						stanzaLines = append(stanzaLines, line)
-					}
-					more := scanner.Scan()
-					if more {
-						line = scanner.Text()
-					} else {
-						break
-					}
-				}
+				var stanzaLines []string
+				stanzaLines, line = consumeStanzaLines(scanner, scanner.Text())
 				if perr := s.ParseFunc(stanzaLines, fields); perr != nil {
 					return perr
 				}
@@ -202,6 +303,13 @@ var InterfaceTable = []*Entry{
 var interfaceTableRE = regexp.MustCompile(`^\s+(.*?)\s+(\d+)`)
 
 func parseInterfaceTable(lines []string, fields map[string]interface{}) error {
+	// InterfaceTable is shared package state, so with per-interface
+	// gathering now calling this repeatedly in one Gather, each call must
+	// start from a clean slate rather than carry over values (or the
+	// missing-field check) from whichever interface was parsed before it.
+	for _, e := range InterfaceTable {
+		e.Value = -1
+	}
 	return storeFieldValues(lines, interfaceTableRE, fields, InterfaceTable)
 }
 
@@ -284,6 +392,35 @@ func (pf *PF) callPfctl() (string, error) {
 var execLookPath = exec.LookPath
 var execCommand = exec.Command
 
+// runPfctl looks up pfctl (wrapping it in doas when UseSudo is set, same as
+// buildPfctlCmd) and runs it with the given arguments, returning its stdout.
+// Unlike gatherInfo's cached PfctlCommand/PfctlArgs, each stanza passes its
+// own arguments here since they each exec a different pfctl subcommand.
+func (pf *PF) runPfctl(args []string) (string, error) {
+	cmd, err := execLookPath(pfctlCommand)
+	if err != nil {
+		return "", fmt.Errorf("can't locate %s: %v", pfctlCommand, err)
+	}
+	if pf.UseSudo {
+		args = append([]string{cmd}, args...)
+		cmd, err = execLookPath("doas")
+		if err != nil {
+			return "", fmt.Errorf("can't locate doas: %v", err)
+		}
+	}
+
+	c := execCommand(cmd, args...)
+	out, oerr := c.Output()
+	if oerr != nil {
+		ee, ok := oerr.(*exec.ExitError)
+		if !ok {
+			return string(out), fmt.Errorf("error running %s: %s: (unable to get stderr)", pfctlCommand, oerr)
+		}
+		return string(out), fmt.Errorf("error running %s: %s: %s", pfctlCommand, oerr, ee.Stderr)
+	}
+	return string(out), nil
+}
+
 func (pf *PF) buildPfctlCmd() (string, []string, error) {
 	cmd, err := execLookPath(pfctlCommand)
 	if err != nil {
@@ -304,6 +441,15 @@ func init() {
 	inputs.Add("pf", func() telegraf.Input {
 		pf := new(PF)
 		pf.infoFunc = pf.callPfctl
+		pf.ioctlFunc = gatherIoctlStats
+		pf.labelsFunc = func() (string, error) { return pf.runPfctl([]string{"-sl"}) }
+		pf.tablesFunc = func() (string, error) { return pf.runPfctl([]string{"-sT", "-vv"}) }
+		pf.queuesFunc = func() (string, error) { return pf.runPfctl([]string{"-sq", "-v"}) }
+		pf.anchorsFunc = func() (string, error) { return pf.runPfctl([]string{"-sA"}) }
+		pf.anchorRulesFunc = func(anchor string) (string, error) {
+			return pf.runPfctl([]string{"-a", anchor, "-sr"})
+		}
+		pf.interfacesFunc = func() (string, error) { return pf.runPfctl([]string{"-vvsI"}) }
 		return pf
 	})
 }
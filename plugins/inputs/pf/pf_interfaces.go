@@ -0,0 +1,67 @@
+package pf
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+)
+
+// interfaceStatsHeaderRE matches the per-interface header pfctl prints for
+// each block of "pfctl -vvsI" output, e.g. "Interface Stats for em0".
+var interfaceStatsHeaderRE = regexp.MustCompile(`^Interface Stats for (\S+)`)
+
+// gatherInterfaces runs "pfctl -vvsI" and emits one "pf" point, tagged by
+// "interface", for every interface name matching pf.Interfaces.
+func (pf *PF) gatherInterfaces(acc telegraf.Accumulator) error {
+	if pf.ifaceFilter == nil {
+		f, err := filter.Compile(pf.Interfaces)
+		if err != nil {
+			return err
+		}
+		pf.ifaceFilter = f
+	}
+
+	o, err := pf.interfacesFunc()
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(o))
+	if !scanner.Scan() {
+		return nil
+	}
+	line := scanner.Text()
+
+	for line != "" {
+		m := interfaceStatsHeaderRE.FindStringSubmatch(line)
+		if m == nil {
+			if !scanner.Scan() {
+				break
+			}
+			line = scanner.Text()
+			continue
+		}
+		name := m[1]
+
+		scanner.Scan()
+		var stanzaLines []string
+		stanzaLines, line = consumeStanzaLines(scanner, scanner.Text())
+
+		if pf.ifaceFilter.Match(name) {
+			fields := make(map[string]interface{})
+			if perr := parseInterfaceTable(stanzaLines, fields); perr != nil {
+				// Don't let one interface with an incomplete stats block
+				// (down link, newly added interface, etc.) keep the rest
+				// of this poll's interfaces from being reported.
+				acc.AddError(fmt.Errorf("interface %q: %s", name, perr))
+			} else {
+				acc.AddFields(measurement, fields, map[string]string{"interface": name})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package pf
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleVvsIOutput = `Interface Stats for em0                  IPv4             IPv6
+  Bytes In                     1024              2048
+  Bytes Out                    4096              8192
+  Packets In
+    Passed                       10                20
+    Blocked                       1                 2
+  Packets Out
+    Passed                       30                40
+    Blocked                       3                 4
+Interface Stats for lo0                  IPv4             IPv6
+  Bytes In                        0
+  Packets In
+    Passed                        0                 0
+    Blocked                       0                 0
+  Packets Out
+    Passed                        0                 0
+    Blocked                       0                 0
+Interface Stats for em1                  IPv4             IPv6
+  Bytes In                      512              1024
+  Bytes Out                    2048              4096
+  Packets In
+    Passed                        5                10
+    Blocked                       1                 1
+  Packets Out
+    Passed                       15                20
+    Blocked                       2                 2
+`
+
+// TestGatherInterfacesSkipsBadBlock ensures an interface whose stats block
+// is missing fields (lo0 above, where "Bytes Out" never shows up) doesn't
+// stop em1's valid block further down from being reported: the error gets
+// recorded but the loop keeps going.
+func TestGatherInterfacesSkipsBadBlock(t *testing.T) {
+	pf := &PF{
+		Interfaces: []string{"*"},
+		interfacesFunc: func() (string, error) {
+			return sampleVvsIOutput, nil
+		},
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherInterfaces(&acc))
+	require.Len(t, acc.Errors, 1)
+
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{
+		"bytes4-in":            int64(1024),
+		"bytes4-out":           int64(4096),
+		"bytes6-in":            int64(2048),
+		"bytes6-out":           int64(8192),
+		"packets4-in-passed":   int64(10),
+		"packets4-in-blocked":  int64(1),
+		"packets4-out-passed":  int64(30),
+		"packets4-out-blocked": int64(3),
+		"packets6-in-passed":   int64(20),
+		"packets6-in-blocked":  int64(2),
+		"packets6-out-passed":  int64(40),
+		"packets6-out-blocked": int64(4),
+	}, map[string]string{"interface": "em0"})
+
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{
+		"bytes4-in":            int64(512),
+		"bytes4-out":           int64(2048),
+		"bytes6-in":            int64(1024),
+		"bytes6-out":           int64(4096),
+		"packets4-in-passed":   int64(5),
+		"packets4-in-blocked":  int64(1),
+		"packets4-out-passed":  int64(15),
+		"packets4-out-blocked": int64(2),
+		"packets6-in-passed":   int64(10),
+		"packets6-in-blocked":  int64(1),
+		"packets6-out-passed":  int64(20),
+		"packets6-out-blocked": int64(2),
+	}, map[string]string{"interface": "em1"})
+
+	for _, m := range acc.Metrics {
+		require.NotEqual(t, "lo0", m.Tags["interface"])
+	}
+}
@@ -0,0 +1,56 @@
+package pf
+
+// devPF is the control device used by the "ioctl" method to read PF state
+// directly from the kernel instead of execing pfctl(8). Opening it requires
+// the calling user to be a member of the "_pf" group (or root); no sudo/doas
+// configuration is needed.
+const devPF = "/dev/pf"
+
+// gatherIoctlStats is provided per-platform: pf_ioctl_freebsd.go and
+// pf_ioctl_openbsd.go implement it with a single DIOCGETSTATUS ioctl, which
+// supplies every field including the state count, while pf_ioctl_other.go stubs it out with an error on platforms
+// that don't have /dev/pf. It is assigned to PF.ioctlFunc in init() so tests
+// can swap it out for a fixture.
+
+// statusCounters mirrors the handful of struct pf_status arrays (from
+// net/pfvar.h) that feed the existing field names in InterfaceTable,
+// StateTable and CounterTable. Keeping this OS-agnostic lets both BSD
+// implementations share one place that maps kernel counters onto the same
+// `fields` keys the pfctl-parsing path already produces.
+type statusCounters struct {
+	// bytes[dir][af]: dir 0=in, 1=out; af 0=IPv4, 1=IPv6
+	bytes [2][2]uint64
+	// packets[dir][action][af]: action 0=passed, 1=blocked
+	packets [2][2][2]uint64
+	// state table operation counters: searches, inserts, removals
+	fcounters [3]uint64
+	// current number of state table entries
+	states uint64
+	// reason-for-drop counters, in the same order as CounterTable
+	counters [15]uint64
+}
+
+func (s *statusCounters) fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"bytes4-in":            s.bytes[0][0],
+		"bytes4-out":           s.bytes[1][0],
+		"bytes6-in":            s.bytes[0][1],
+		"bytes6-out":           s.bytes[1][1],
+		"packets4-in-passed":   s.packets[0][0][0],
+		"packets4-in-blocked":  s.packets[0][1][0],
+		"packets4-out-passed":  s.packets[1][0][0],
+		"packets4-out-blocked": s.packets[1][1][0],
+		"packets6-in-passed":   s.packets[0][0][1],
+		"packets6-in-blocked":  s.packets[0][1][1],
+		"packets6-out-passed":  s.packets[1][0][1],
+		"packets6-out-blocked": s.packets[1][1][1],
+		"entries":              s.states,
+		"searches":             s.fcounters[0],
+		"inserts":              s.fcounters[1],
+		"removals":             s.fcounters[2],
+	}
+	for i, entry := range CounterTable {
+		fields[entry.Field] = s.counters[i]
+	}
+	return fields
+}
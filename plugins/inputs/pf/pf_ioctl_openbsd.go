@@ -0,0 +1,71 @@
+//go:build openbsd
+// +build openbsd
+
+package pf
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// pfStatus mirrors the prefix of struct pf_status from
+// /usr/include/net/pfvar.h that we care about: the reason-for-drop
+// counters, state table operation counters and the per-direction/per-af
+// packet and byte counters. DIOCGETSTATUS copies out the whole kernel
+// struct, so the fields we don't read are left as trailing padding that
+// brings pfStatus up to the 488 bytes the ioctl number encodes.
+type pfStatus struct {
+	counters  [15]uint64
+	lcounters [8]uint64
+	fcounters [3]uint64
+	scounters [3]uint64
+	pcounters [2][2][2]uint64
+	bcounters [2][2]uint64
+	running   uint32
+	states    uint32
+	_         [152]byte
+}
+
+func gatherIoctlStats() (map[string]interface{}, error) {
+	f, err := os.OpenFile(devPF, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %s", devPF, err)
+	}
+	defer f.Close()
+
+	var status pfStatus
+	// DIOCGETSTATUS comes from golang.org/x/sys/unix rather than a
+	// hand-derived _IOWR encoding, since that encoding depends on the exact
+	// size of the kernel's struct pf_status and is easy to get wrong by hand.
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), unix.DIOCGETSTATUS, uintptr(unsafe.Pointer(&status))); errno != 0 {
+		return nil, fmt.Errorf("DIOCGETSTATUS ioctl on %s failed: %s", devPF, errno)
+	}
+
+	sc := &statusCounters{
+		bytes:     status.bcounters,
+		packets:   pcountersToPackets(status.pcounters),
+		fcounters: status.fcounters,
+		counters:  status.counters,
+	}
+	sc.states = uint64(status.states)
+
+	return sc.fields(), nil
+}
+
+// pcountersToPackets converts pf_status's pcounters, indexed
+// [dir][af][action], into statusCounters.packets' [dir][action][af]
+// ordering.
+func pcountersToPackets(pcounters [2][2][2]uint64) [2][2][2]uint64 {
+	var packets [2][2][2]uint64
+	for dir := 0; dir < 2; dir++ {
+		for af := 0; af < 2; af++ {
+			for action := 0; action < 2; action++ {
+				packets[dir][action][af] = pcounters[dir][af][action]
+			}
+		}
+	}
+	return packets
+}
@@ -0,0 +1,35 @@
+//go:build openbsd
+// +build openbsd
+
+package pf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPcountersToPackets(t *testing.T) {
+	// pcounters[dir][af][action]
+	pcounters := [2][2][2]uint64{
+		{ // in
+			{10, 30}, // IPv4: passed, blocked
+			{20, 40}, // IPv6: passed, blocked
+		},
+		{ // out
+			{50, 70},
+			{60, 80},
+		},
+	}
+
+	packets := pcountersToPackets(pcounters)
+
+	require.EqualValues(t, 10, packets[0][0][0]) // in, passed, v4
+	require.EqualValues(t, 30, packets[0][1][0]) // in, blocked, v4
+	require.EqualValues(t, 20, packets[0][0][1]) // in, passed, v6
+	require.EqualValues(t, 40, packets[0][1][1]) // in, blocked, v6
+	require.EqualValues(t, 50, packets[1][0][0]) // out, passed, v4
+	require.EqualValues(t, 70, packets[1][1][0]) // out, blocked, v4
+	require.EqualValues(t, 60, packets[1][0][1]) // out, passed, v6
+	require.EqualValues(t, 80, packets[1][1][1]) // out, blocked, v6
+}
@@ -0,0 +1,12 @@
+//go:build !freebsd && !openbsd
+// +build !freebsd,!openbsd
+
+package pf
+
+import "fmt"
+
+// gatherIoctlStats is a stub on platforms other than FreeBSD/OpenBSD, which
+// don't expose /dev/pf.
+func gatherIoctlStats() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("method \"ioctl\" is not supported on this platform")
+}
@@ -0,0 +1,60 @@
+package pf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatusCountersFields pins down the [dir][action][af] convention
+// statusCounters.fields() expects, so the per-platform ioctl code (which
+// copies from the kernel's [dir][af][action]-ordered pcounters into this
+// struct) has something to be checked against without needing a live
+// FreeBSD/OpenBSD kernel.
+func TestStatusCountersFields(t *testing.T) {
+	sc := &statusCounters{
+		bytes: [2][2]uint64{
+			{1, 2}, // in:  IPv4, IPv6
+			{3, 4}, // out: IPv4, IPv6
+		},
+		packets: [2][2][2]uint64{
+			{ // in
+				{10, 20}, // passed: IPv4, IPv6
+				{30, 40}, // blocked: IPv4, IPv6
+			},
+			{ // out
+				{50, 60},
+				{70, 80},
+			},
+		},
+		fcounters: [3]uint64{100, 200, 300},
+		states:    400,
+		counters:  [15]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	}
+
+	fields := sc.fields()
+
+	require.EqualValues(t, 1, fields["bytes4-in"])
+	require.EqualValues(t, 3, fields["bytes4-out"])
+	require.EqualValues(t, 2, fields["bytes6-in"])
+	require.EqualValues(t, 4, fields["bytes6-out"])
+
+	require.EqualValues(t, 10, fields["packets4-in-passed"])
+	require.EqualValues(t, 30, fields["packets4-in-blocked"])
+	require.EqualValues(t, 50, fields["packets4-out-passed"])
+	require.EqualValues(t, 70, fields["packets4-out-blocked"])
+
+	require.EqualValues(t, 20, fields["packets6-in-passed"])
+	require.EqualValues(t, 40, fields["packets6-in-blocked"])
+	require.EqualValues(t, 60, fields["packets6-out-passed"])
+	require.EqualValues(t, 80, fields["packets6-out-blocked"])
+
+	require.EqualValues(t, 400, fields["entries"])
+	require.EqualValues(t, 100, fields["searches"])
+	require.EqualValues(t, 200, fields["inserts"])
+	require.EqualValues(t, 300, fields["removals"])
+
+	for i, entry := range CounterTable {
+		require.EqualValues(t, i+1, fields[entry.Field])
+	}
+}
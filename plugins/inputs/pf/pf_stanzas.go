@@ -0,0 +1,222 @@
+package pf
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+)
+
+// This file deliberately doesn't reuse the Entry/storeFieldValues machinery
+// in pf.go: that machinery matches a fixed table of known PfctlTitle strings
+// against lines to build one flat, untagged field map per Gather call, which
+// fits the single "info" stanza it was built for. Labels/tables/queues/
+// anchors instead each describe a variable-length list of entities (any
+// number of labels, tables, queues or anchors per poll, unknown ahead of time) that need
+// their own tagged point apiece, so they're parsed and emitted independently
+// below rather than forced through a helper built around a single fixed
+// field set.
+const labelsMeasurement = "pf_labels"
+const tablesMeasurement = "pf_tables"
+const queuesMeasurement = "pf_queues"
+const anchorsMeasurement = "pf_anchors"
+
+// gatherLabels runs "pfctl -sl" and emits one pf_labels point per rule
+// label. Each line looks like:
+//
+//	web_allow 10420 8500000 250 3 1
+//
+// label, evaluations, packets, bytes, states_created, states_current
+func (pf *PF) gatherLabels(acc telegraf.Accumulator) error {
+	o, err := pf.labelsFunc()
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(o))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 6 {
+			continue
+		}
+		values := make([]int64, 5)
+		valid := true
+		for i, f := range fields[1:] {
+			v, perr := strconv.ParseInt(f, 10, 64)
+			if perr != nil {
+				valid = false
+				break
+			}
+			values[i] = v
+		}
+		if !valid {
+			continue
+		}
+		acc.AddFields(labelsMeasurement, map[string]interface{}{
+			"evaluations":    values[0],
+			"packets":        values[1],
+			"bytes":          values[2],
+			"states_created": values[3],
+			"states_current": values[4],
+		}, map[string]string{"label": fields[0]})
+	}
+	return nil
+}
+
+// tableHeaderRE matches the "-- <name>" header pfctl prints for each table
+// in "pfctl -sT -vv" output.
+var tableHeaderRE = regexp.MustCompile(`^--\s+(\S+)`)
+var tableAddressesRE = regexp.MustCompile(`Addresses:\s+(\d+)`)
+var tablePacketsBytesRE = regexp.MustCompile(`Packets:\s*(\d+)\s+Bytes:\s*(\d+)`)
+
+// gatherTables runs "pfctl -sT -vv" and emits one pf_tables point per table,
+// with the packet/byte match counts summed across the In/Out, Block/Pass
+// directions pfctl prints separately.
+func (pf *PF) gatherTables(acc telegraf.Accumulator) error {
+	o, err := pf.tablesFunc()
+	if err != nil {
+		return err
+	}
+
+	var name string
+	var addresses, packets, bytes int64
+	haveTable := false
+
+	flush := func() {
+		if !haveTable {
+			return
+		}
+		acc.AddFields(tablesMeasurement, map[string]interface{}{
+			"addresses": addresses,
+			"packets":   packets,
+			"bytes":     bytes,
+		}, map[string]string{"table": name})
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(o))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := tableHeaderRE.FindStringSubmatch(line); m != nil {
+			flush()
+			name = m[1]
+			addresses, packets, bytes = 0, 0, 0
+			haveTable = true
+			continue
+		}
+		if !haveTable {
+			continue
+		}
+		if m := tableAddressesRE.FindStringSubmatch(line); m != nil {
+			addresses, _ = strconv.ParseInt(m[1], 10, 64)
+		}
+		if m := tablePacketsBytesRE.FindStringSubmatch(line); m != nil {
+			p, _ := strconv.ParseInt(m[1], 10, 64)
+			b, _ := strconv.ParseInt(m[2], 10, 64)
+			packets += p
+			bytes += b
+		}
+	}
+	flush()
+	return nil
+}
+
+// queueHeaderRE matches the "queue <name> on <interface> ..." line pfctl
+// prints before each queue's stats in "pfctl -sq -v" output.
+var queueHeaderRE = regexp.MustCompile(`^queue\s+(\S+)\s+on\s+(\S+)`)
+var queueStatsRE = regexp.MustCompile(`pkts:\s*(\d+)\s+bytes:\s*(\d+)\s+dropped pkts:\s*(\d+)\s+bytes:\s*(\d+)`)
+var queueLengthRE = regexp.MustCompile(`qlength:\s*(\d+)/\s*(\d+)`)
+
+// gatherQueues runs "pfctl -sq -v" and emits one pf_queues point per ALTQ
+// queue.
+func (pf *PF) gatherQueues(acc telegraf.Accumulator) error {
+	o, err := pf.queuesFunc()
+	if err != nil {
+		return err
+	}
+
+	var name, iface string
+	fields := make(map[string]interface{})
+	haveQueue := false
+
+	flush := func() {
+		if !haveQueue {
+			return
+		}
+		acc.AddFields(queuesMeasurement, fields, map[string]string{"queue": name, "interface": iface})
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(o))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := queueHeaderRE.FindStringSubmatch(line); m != nil {
+			flush()
+			name, iface = m[1], m[2]
+			fields = make(map[string]interface{})
+			haveQueue = true
+			continue
+		}
+		if !haveQueue {
+			continue
+		}
+		if m := queueStatsRE.FindStringSubmatch(line); m != nil {
+			fields["packets"], _ = strconv.ParseInt(m[1], 10, 64)
+			fields["bytes"], _ = strconv.ParseInt(m[2], 10, 64)
+			fields["dropped_packets"], _ = strconv.ParseInt(m[3], 10, 64)
+			fields["dropped_bytes"], _ = strconv.ParseInt(m[4], 10, 64)
+		}
+		if m := queueLengthRE.FindStringSubmatch(line); m != nil {
+			fields["qlength"], _ = strconv.ParseInt(m[1], 10, 64)
+			fields["qlimit"], _ = strconv.ParseInt(m[2], 10, 64)
+		}
+	}
+	flush()
+	return nil
+}
+
+// anchorNameRE matches one line of "pfctl -sA" output: a bare anchor name,
+// optionally nested ("foo/bar").
+var anchorNameRE = regexp.MustCompile(`^(\S+)`)
+
+// gatherAnchors runs "pfctl -sA" to enumerate anchors, then "pfctl -a
+// <anchor> -sr" for each one to count its rules, emitting one pf_anchors
+// point per anchor.
+func (pf *PF) gatherAnchors(acc telegraf.Accumulator) error {
+	o, err := pf.anchorsFunc()
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(o))
+	for scanner.Scan() {
+		m := anchorNameRE.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		anchor := m[1]
+
+		rules, rerr := pf.anchorRulesFunc(anchor)
+		if rerr != nil {
+			// Don't let one anchor pfctl can't list rules for (removed
+			// mid-poll, etc.) keep the rest of this poll's anchors from
+			// being reported.
+			acc.AddError(fmt.Errorf("error listing rules for anchor %q: %s", anchor, rerr))
+			continue
+		}
+
+		count := 0
+		ruleScanner := bufio.NewScanner(strings.NewReader(rules))
+		for ruleScanner.Scan() {
+			if strings.TrimSpace(ruleScanner.Text()) != "" {
+				count++
+			}
+		}
+
+		acc.AddFields(anchorsMeasurement, map[string]interface{}{
+			"rules": int64(count),
+		}, map[string]string{"anchor": anchor})
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package pf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherLabels(t *testing.T) {
+	pf := &PF{
+		labelsFunc: func() (string, error) {
+			return "web_allow 10420 8500000 250 3 1\nssh_block 42 0 0 0 0\n", nil
+		},
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherLabels(&acc))
+
+	acc.AssertContainsTaggedFields(t, labelsMeasurement, map[string]interface{}{
+		"evaluations":    int64(10420),
+		"packets":        int64(8500000),
+		"bytes":          int64(250),
+		"states_created": int64(3),
+		"states_current": int64(1),
+	}, map[string]string{"label": "web_allow"})
+
+	acc.AssertContainsTaggedFields(t, labelsMeasurement, map[string]interface{}{
+		"evaluations":    int64(42),
+		"packets":        int64(0),
+		"bytes":          int64(0),
+		"states_created": int64(0),
+		"states_current": int64(0),
+	}, map[string]string{"label": "ssh_block"})
+}
+
+const sampleTablesOutput = `-- blocklist
+        Addresses:   3
+        Cleared:     Mon Jan  1 00:00:00 2024
+        References:  [ Anchors: 0                  Rules: 1 ]
+        Evaluations: [ NoMatch: 0                   Match: 532 ]
+           In/Block: [ Packets: 5                   Bytes: 420 ]
+           In/Pass:  [ Packets: 0                    Bytes: 0 ]
+          Out/Block: [ Packets: 0                    Bytes: 0 ]
+          Out/Pass:  [ Packets: 527                  Bytes: 45000 ]
+-- allowlist
+        Addresses:   1
+        Evaluations: [ NoMatch: 0                   Match: 10 ]
+           In/Block: [ Packets: 0                    Bytes: 0 ]
+           In/Pass:  [ Packets: 10                   Bytes: 900 ]
+          Out/Block: [ Packets: 0                    Bytes: 0 ]
+          Out/Pass:  [ Packets: 0                    Bytes: 0 ]
+`
+
+func TestGatherTables(t *testing.T) {
+	pf := &PF{
+		tablesFunc: func() (string, error) { return sampleTablesOutput, nil },
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherTables(&acc))
+
+	acc.AssertContainsTaggedFields(t, tablesMeasurement, map[string]interface{}{
+		"addresses": int64(3),
+		"packets":   int64(5 + 0 + 0 + 527),
+		"bytes":     int64(420 + 0 + 0 + 45000),
+	}, map[string]string{"table": "blocklist"})
+
+	acc.AssertContainsTaggedFields(t, tablesMeasurement, map[string]interface{}{
+		"addresses": int64(1),
+		"packets":   int64(0 + 10 + 0 + 0),
+		"bytes":     int64(0 + 900 + 0 + 0),
+	}, map[string]string{"table": "allowlist"})
+}
+
+const sampleQueuesOutput = `queue root_em0 on em0 bandwidth 100Mb priority 0
+  [ pkts:      1234  bytes:   567890  dropped pkts:      3 bytes:     900 ]
+  [ qlength:   0/ 50 ]
+queue voip on em0 bandwidth 10Mb priority 5
+  [ pkts:       500  bytes:    50000  dropped pkts:      0 bytes:       0 ]
+  [ qlength:   2/ 20 ]
+`
+
+func TestGatherQueues(t *testing.T) {
+	pf := &PF{
+		queuesFunc: func() (string, error) { return sampleQueuesOutput, nil },
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherQueues(&acc))
+
+	acc.AssertContainsTaggedFields(t, queuesMeasurement, map[string]interface{}{
+		"packets":         int64(1234),
+		"bytes":           int64(567890),
+		"dropped_packets": int64(3),
+		"dropped_bytes":   int64(900),
+		"qlength":         int64(0),
+		"qlimit":          int64(50),
+	}, map[string]string{"queue": "root_em0", "interface": "em0"})
+
+	acc.AssertContainsTaggedFields(t, queuesMeasurement, map[string]interface{}{
+		"packets":         int64(500),
+		"bytes":           int64(50000),
+		"dropped_packets": int64(0),
+		"dropped_bytes":   int64(0),
+		"qlength":         int64(2),
+		"qlimit":          int64(20),
+	}, map[string]string{"queue": "voip", "interface": "em0"})
+}
+
+func TestGatherAnchors(t *testing.T) {
+	pf := &PF{
+		anchorsFunc: func() (string, error) { return "authpf\nnat-anchor\n", nil },
+		anchorRulesFunc: func(anchor string) (string, error) {
+			switch anchor {
+			case "authpf":
+				return "block all\npass in\npass out\n", nil
+			case "nat-anchor":
+				return "", nil
+			default:
+				t.Fatalf("unexpected anchor %q", anchor)
+				return "", nil
+			}
+		},
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherAnchors(&acc))
+
+	acc.AssertContainsTaggedFields(t, anchorsMeasurement, map[string]interface{}{
+		"rules": int64(3),
+	}, map[string]string{"anchor": "authpf"})
+
+	acc.AssertContainsTaggedFields(t, anchorsMeasurement, map[string]interface{}{
+		"rules": int64(0),
+	}, map[string]string{"anchor": "nat-anchor"})
+}
+
+// TestGatherAnchorsSkipsBadAnchor ensures an anchor whose rules can't be
+// listed doesn't stop nat-anchor further down from being reported: the
+// error gets recorded but the loop keeps going.
+func TestGatherAnchorsSkipsBadAnchor(t *testing.T) {
+	pf := &PF{
+		anchorsFunc: func() (string, error) { return "authpf\nnat-anchor\n", nil },
+		anchorRulesFunc: func(anchor string) (string, error) {
+			switch anchor {
+			case "authpf":
+				return "", fmt.Errorf("pfctl: anchor does not exist")
+			case "nat-anchor":
+				return "pass out\n", nil
+			default:
+				t.Fatalf("unexpected anchor %q", anchor)
+				return "", nil
+			}
+		},
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, pf.gatherAnchors(&acc))
+	require.Len(t, acc.Errors, 1)
+
+	acc.AssertContainsTaggedFields(t, anchorsMeasurement, map[string]interface{}{
+		"rules": int64(1),
+	}, map[string]string{"anchor": "nat-anchor"})
+
+	for _, m := range acc.Metrics {
+		require.NotEqual(t, "authpf", m.Tags["anchor"])
+	}
+}
@@ -0,0 +1,86 @@
+package pf
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleInfoOutput = `Status: Enabled for 0 days 01:23:45		Debug: Urgent
+
+Interface Stats for em0                  IPv4             IPv6
+  Bytes In                     1024              2048
+  Bytes Out                    4096              8192
+  Packets In
+    Passed                       10                20
+    Blocked                       1                 2
+  Packets Out
+    Passed                       30                40
+    Blocked                       3                 4
+State Table                          Total             Rate
+  current entries                       5
+  searches                            100             1.0/s
+  inserts                              10             0.1/s
+  removals                              5             0.0/s
+Counters
+  match                                 42             0.0/s
+  bad-offset                             0             0.0/s
+  fragment                               0             0.0/s
+  short                                  0             0.0/s
+  normalize                              0             0.0/s
+  memory                                 0             0.0/s
+  bad-timestamp                          0             0.0/s
+  congestion                             0             0.0/s
+  ip-option                              0             0.0/s
+  proto-cksum                            0             0.0/s
+  state-mismatch                         0             0.0/s
+  state-insert                           0             0.0/s
+  state-limit                            0             0.0/s
+  src-limit                              0             0.0/s
+  synproxy                               0             0.0/s
+`
+
+// TestParsePfctlOutput exercises the "info" stanza parsing against a
+// representative "pfctl -s info" fixture, covering the Packets/Bytes
+// normalization consumeStanzaLines performs.
+func TestParsePfctlOutput(t *testing.T) {
+	pf := &PF{}
+	var acc testutil.Accumulator
+
+	require.NoError(t, pf.parsePfctlOutput(sampleInfoOutput, &acc))
+
+	acc.AssertContainsFields(t, measurement, map[string]interface{}{
+		"bytes4-in":            int64(1024),
+		"bytes4-out":           int64(4096),
+		"bytes6-in":            int64(2048),
+		"bytes6-out":           int64(8192),
+		"packets4-in-passed":   int64(10),
+		"packets4-in-blocked":  int64(1),
+		"packets4-out-passed":  int64(30),
+		"packets4-out-blocked": int64(3),
+		"packets6-in-passed":   int64(20),
+		"packets6-in-blocked":  int64(2),
+		"packets6-out-passed":  int64(40),
+		"packets6-out-blocked": int64(4),
+		"entries":              int64(5),
+		"searches":             int64(100),
+		"inserts":              int64(10),
+		"removals":             int64(5),
+		"match":                int64(42),
+		"bad-offset":           int64(0),
+		"fragment":             int64(0),
+		"short":                int64(0),
+		"normalize":            int64(0),
+		"memory":               int64(0),
+		"bad-timestamp":        int64(0),
+		"congestion":           int64(0),
+		"ip-option":            int64(0),
+		"proto-cksum":          int64(0),
+		"state-mismatch":       int64(0),
+		"state-insert":         int64(0),
+		"state-limit":          int64(0),
+		"src-limit":            int64(0),
+		"synproxy":             int64(0),
+	})
+}